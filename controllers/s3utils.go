@@ -17,17 +17,30 @@ import (
 	"bytes"
 	"compress/gzip"
 	"context"
+	"crypto/md5" //nolint:gosec // not used for cryptographic purposes; required by SSE-C
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"io"
+	"net/http"
+	"os"
 	"reflect"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/credentials/ec2rolecreds"
+	"github.com/aws/aws-sdk-go/aws/credentials/stscreds"
+	"github.com/aws/aws-sdk-go/aws/ec2metadata"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/s3"
 	"github.com/aws/aws-sdk-go/service/s3/s3manager"
+	"github.com/aws/aws-sdk-go/service/sts"
 	"github.com/go-logr/logr"
 	errorswrapper "github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
@@ -35,6 +48,156 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
+// CredentialSourceType identifies how an ObjectStore should obtain its AWS
+// credentials.  It is the type of S3StoreProfile's CredentialSource field.
+type CredentialSourceType string
+
+const (
+	// CredentialSourceSecret builds static credentials from the Secret
+	// referenced by S3SecretRef.  This is the long-standing default.
+	CredentialSourceSecret CredentialSourceType = "secret"
+	// CredentialSourceIRSA assumes the IAM role configured for the pod's
+	// service account via IAM Roles for Service Accounts (IRSA), reading
+	// AWS_ROLE_ARN and AWS_WEB_IDENTITY_TOKEN_FILE from the environment.
+	CredentialSourceIRSA CredentialSourceType = "irsa"
+	// CredentialSourceEC2Instance obtains credentials from the EC2 instance
+	// metadata service, as granted by the instance's IAM instance profile.
+	CredentialSourceEC2Instance CredentialSourceType = "ec2-instance"
+	// CredentialSourceChain tries, in order, the environment, shared config,
+	// IRSA web identity, and EC2 instance profile credential providers.
+	CredentialSourceChain CredentialSourceType = "chain"
+)
+
+// SSEMode identifies which of the mutually exclusive server-side encryption
+// schemes an S3StoreProfile's Encryption stanza selects.  It is a
+// discriminated union: at most one of the corresponding Encryption fields is
+// consulted, based on the value of Encryption.Mode.
+type SSEMode string
+
+const (
+	// SSEModeNone uploads objects unencrypted, the historical behavior.
+	SSEModeNone SSEMode = ""
+	// SSEModeS3 requests SSE-S3 (AES256, S3-managed keys).
+	SSEModeS3 SSEMode = "sseS3"
+	// SSEModeKMS requests SSE-KMS using Encryption.SSEKMSKeyID, optionally
+	// scoped by Encryption.SSEKMSEncryptionContext.
+	SSEModeKMS SSEMode = "sseKMS"
+	// SSEModeC requests SSE-C using a customer-supplied 32-byte key loaded
+	// from the Secret referenced by Encryption.SSECSecretRef.
+	SSEModeC SSEMode = "sseC"
+)
+
+// RetentionMode is the S3 Object Lock retention mode of an S3StoreProfile's
+// Retention stanza, mirroring MinIO/S3's GOVERNANCE and COMPLIANCE modes.
+type RetentionMode string
+
+const (
+	// RetentionModeNone disables Object Lock; the historical behavior.
+	RetentionModeNone RetentionMode = ""
+	// RetentionModeGovernance allows a caller with s3:BypassGovernanceRetention
+	// permission to delete or overwrite a locked object before its retain-until
+	// date.
+	RetentionModeGovernance RetentionMode = "GOVERNANCE"
+	// RetentionModeCompliance forbids deleting or overwriting a locked object
+	// before its retain-until date, even for the bucket owner.
+	RetentionModeCompliance RetentionMode = "COMPLIANCE"
+)
+
+// retentionConfig is the resolved, ready-to-use form of an S3StoreProfile's
+// Retention stanza.
+type retentionConfig struct {
+	mode      RetentionMode
+	days      int64
+	legalHold bool
+}
+
+// resolveRetentionConfig reads the Retention stanza of an S3StoreProfile.
+func resolveRetentionConfig(profile S3StoreProfile) retentionConfig {
+	return retentionConfig{
+		mode:      profile.Retention.Mode,
+		days:      int64(profile.Retention.Days),
+		legalHold: profile.Retention.LegalHold,
+	}
+}
+
+// ErrObjectLocked is returned by DeleteObjects/PurgeBucket in place of
+// bailing the whole batch when S3 refuses a delete because the object is
+// under an Object Lock retention period or legal hold.
+type ErrObjectLocked struct {
+	Key string
+	Err error
+}
+
+func (e *ErrObjectLocked) Error() string {
+	return fmt.Sprintf("object %s is locked and could not be deleted: %v", e.Key, e.Err)
+}
+
+func (e *ErrObjectLocked) Unwrap() error {
+	return e.Err
+}
+
+// isObjectLockedErr returns true if err is an AWS error code S3 returns when
+// a delete is refused due to Object Lock retention or a legal hold.
+func isObjectLockedErr(err error) bool {
+	var aerr awserr.Error
+	if errorswrapper.As(err, &aerr) {
+		switch aerr.Code() {
+		case "AccessDenied", "InvalidRequest":
+			return true
+		}
+	}
+
+	return false
+}
+
+// sseConfig is the resolved, ready-to-use form of an S3StoreProfile's
+// Encryption stanza: the customer key (for SSE-C) has already been read
+// from its Secret and validated.
+type sseConfig struct {
+	mode           SSEMode
+	kmsKeyID       string
+	kmsContext     string
+	customerKey    []byte // 32 raw bytes, only set when mode is SSEModeC
+	customerKeyMD5 string // base64-encoded MD5 of customerKey
+}
+
+// resolveSSEConfig reads and validates the Encryption stanza of an
+// S3StoreProfile, loading the SSE-C customer key from its Secret when
+// required.
+func resolveSSEConfig(ctx context.Context, r client.Reader,
+	profile S3StoreProfile) (sseConfig, error) {
+	enc := profile.Encryption
+	if enc.Mode != SSEModeC {
+		return sseConfig{
+			mode:       enc.Mode,
+			kmsKeyID:   enc.SSEKMSKeyID,
+			kmsContext: enc.SSEKMSEncryptionContext,
+		}, nil
+	}
+
+	secret := corev1.Secret{}
+	if err := r.Get(ctx,
+		types.NamespacedName{Namespace: enc.SSECSecretRef.Namespace, Name: enc.SSECSecretRef.Name},
+		&secret); err != nil {
+		return sseConfig{}, fmt.Errorf("failed to get SSE-C key secret %v, %w",
+			enc.SSECSecretRef, err)
+	}
+
+	key := secret.Data["key"]
+	if len(key) != 32 {
+		return sseConfig{}, fmt.Errorf("SSE-C key in secret %v must be 32 bytes, got %d",
+			enc.SSECSecretRef, len(key))
+	}
+
+	sum := md5.Sum(key) //nolint:gosec // MD5 here is the S3 SSE-C key-integrity checksum, not a security control
+
+	return sseConfig{
+		mode:           SSEModeC,
+		customerKey:    key,
+		customerKeyMD5: base64.StdEncoding.EncodeToString(sum[:]),
+	}, nil
+}
+
 // Example usage:
 // func example_code() {
 // *** setup a new s3 object store ***
@@ -99,13 +262,47 @@ type ObjectStorer interface {
 		uploadContent interface{}) error
 	VerifyPVUpload(pvKeyPrefix, pvKeySuffix string,
 		verifyPV corev1.PersistentVolume) error
-	DownloadPVs(pvKeyPrefix string) (
+	// DownloadPVs downloads, for each matching key, the newest version as of
+	// the optional `at` time, or the current version if `at` is omitted.
+	DownloadPVs(pvKeyPrefix string, at ...time.Time) (
 		pvList []corev1.PersistentVolume, err error)
+	// DownloadTypedObjects downloads, for each matching key, the newest
+	// version as of the optional `at` time, or the current version if `at`
+	// is omitted.
 	DownloadTypedObjects(keyPrefix string,
-		objectType reflect.Type) (interface{}, error)
+		objectType reflect.Type, at ...time.Time) (interface{}, error)
 	ListKeys(keyPrefix string) (keys []string, err error)
 	DownloadObject(key string, downloadContent interface{}) error
 	DeleteObjects(keyPrefix string) error
+	// EnableBucketVersioning turns on S3 object versioning for the bucket,
+	// a prerequisite for ListVersions/DownloadObjectVersion/DeleteObjectVersion.
+	EnableBucketVersioning(bucket string) error
+	// ListVersions lists every version of every key under keyPrefix,
+	// including delete markers.  Delete markers follow the object versions
+	// of the same page; callers must not rely on any newest-first order.
+	ListVersions(keyPrefix string) ([]ObjectVersion, error)
+	// DownloadObjectVersion downloads a specific version of key into
+	// downloadContent.
+	DownloadObjectVersion(key, versionID string, downloadContent interface{}) error
+	// DeleteObjectVersion deletes a specific version of key.
+	DeleteObjectVersion(key, versionID string) error
+	// ConfigureBucketObjectLock sets the bucket's default retention, per the
+	// profile's Retention stanza, once Object Lock is enabled on it.
+	// CreateBucket enables Object Lock at creation time whenever Retention is
+	// configured; a bucket that predates Retention being configured, or that
+	// Ramen did not create itself, must have Object Lock enabled out of band
+	// since there is no API to retrofit it.
+	ConfigureBucketObjectLock(bucket string) error
+}
+
+// ObjectVersion describes one version of one key, as returned by
+// ListVersions.
+type ObjectVersion struct {
+	Key            string
+	VersionID      string
+	LastModified   time.Time
+	IsLatest       bool
+	IsDeleteMarker bool
 }
 
 // S3ObjectStoreGetter returns a concrete type that implements
@@ -119,6 +316,17 @@ func S3ObjectStoreGetter() ObjectStoreGetter {
 // the ObjectStoreGetter interface.
 type s3ObjectStoreGetter struct{}
 
+// sessionCache caches, per s3 profile name, a previously established
+// session so that repeated ObjectStore() calls for the same profile do not
+// pay the cost of re-resolving credentials (and, for IRSA/instance-profile
+// sources, re-assuming the role) on every reconcile.
+//
+//nolint:gochecknoglobals
+var (
+	sessionCache   = map[string]*s3ObjectStore{}
+	sessionCacheMu sync.Mutex
+)
+
 // ObjectStore returns an S3 object store that satisfies the ObjectStorer
 // interface,  with a downloader and an uploader client connections, by either
 // creating a new connection or returning a previously established connection
@@ -133,32 +341,105 @@ func (s3ObjectStoreGetter) ObjectStore(ctx context.Context,
 			s3ProfileName, callerTag, err)
 	}
 
-	accessID, secretAccessKey, err := GetS3Secret(ctx, r, s3StoreProfile.S3SecretRef)
+	fingerprint, err := profileCacheFingerprint(ctx, r, s3StoreProfile)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get secret %v for caller %s, %w",
-			s3StoreProfile.S3SecretRef, callerTag, err)
+		return nil, fmt.Errorf("failed to read profile %s secrets for caller %s, %w",
+			s3ProfileName, callerTag, err)
+	}
+
+	sessionCacheMu.Lock()
+	if s3Conn, ok := sessionCache[s3ProfileName]; ok && s3Conn.profileFingerprint == fingerprint {
+		sessionCacheMu.Unlock()
+
+		s3Conn.callerTag = callerTag
+
+		return s3Conn, nil
 	}
+	sessionCacheMu.Unlock()
 
 	s3Endpoint := s3StoreProfile.S3CompatibleEndpoint
 	s3Region := s3StoreProfile.S3Region
 
-	// Create an S3 client session
-	s3Session, err := session.NewSession(&aws.Config{
-		Credentials: credentials.NewStaticCredentials(string(accessID),
-			string(secretAccessKey), ""),
+	httpClient, err := buildS3HTTPClient(ctx, r, s3StoreProfile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build TLS config for %s for caller %s, %w",
+			s3Endpoint, callerTag, err)
+	}
+
+	s3ForcePathStyle := !strings.Contains(s3Endpoint, "amazonaws.com")
+	if s3StoreProfile.ForcePathStyle != nil {
+		s3ForcePathStyle = *s3StoreProfile.ForcePathStyle
+	}
+
+	awsConfig := &aws.Config{
 		Endpoint:         aws.String(s3Endpoint),
 		Region:           aws.String(s3Region),
-		DisableSSL:       aws.Bool(true),
-		S3ForcePathStyle: aws.Bool(true),
-	})
+		DisableSSL:       aws.Bool(false),
+		S3ForcePathStyle: aws.Bool(s3ForcePathStyle),
+		HTTPClient:       httpClient,
+	}
+
+	credSource := s3StoreProfile.CredentialSource
+	if credSource == "" {
+		credSource = CredentialSourceSecret
+	}
+
+	if credSource == CredentialSourceSecret {
+		if s3StoreProfile.S3SecretRef == (corev1.SecretReference{}) {
+			return nil, fmt.Errorf("no S3SecretRef configured for profile %s for caller %s",
+				s3ProfileName, callerTag)
+		}
+
+		accessID, secretAccessKey, err := GetS3Secret(ctx, r, s3StoreProfile.S3SecretRef)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get secret %v for caller %s, %w",
+				s3StoreProfile.S3SecretRef, callerTag, err)
+		}
+
+		awsConfig.Credentials = credentials.NewStaticCredentials(
+			string(accessID), string(secretAccessKey), "")
+	} else {
+		// Credential chains that do not require a Kubernetes Secret need a
+		// bootstrap session to assume a role or reach instance metadata.  This
+		// session must NOT carry the S3-compatible Endpoint: session.Session
+		// applies a non-empty Endpoint to every service client derived from
+		// it, so sts.New/ec2metadata.New below would otherwise be pointed at
+		// the S3 endpoint instead of the real STS/instance-metadata hosts.
+		bootstrapSession, err := session.NewSession(&aws.Config{
+			Region: aws.String(s3Region),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to create bootstrap session for %s for caller %s, %w",
+				s3Endpoint, callerTag, err)
+		}
+
+		providerCreds, err := credentialsForSource(credSource, bootstrapSession)
+		if err != nil {
+			return nil, fmt.Errorf("failed to build %s credentials for profile %s for caller %s, %w",
+				credSource, s3ProfileName, callerTag, err)
+		}
+
+		awsConfig.Credentials = providerCreds
+	}
+
+	// Create an S3 client session
+	s3Session, err := session.NewSession(awsConfig)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create new session for %s for caller %s, %w",
 			s3Endpoint, callerTag, err)
 	}
 
+	sse, err := resolveSSEConfig(ctx, r, s3StoreProfile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve encryption config for profile %s for caller %s, %w",
+			s3ProfileName, callerTag, err)
+	}
+
 	// Create a client session
 	s3Client := s3.New(s3Session)
 
+	warnIfBucketUnencrypted(s3Client, s3StoreProfile.S3Bucket, callerTag, log)
+
 	// Also create S3 uploader and S3 downloader which can be safely used
 	// concurrently across goroutines, whereas, the s3 client session
 	// does not support concurrent writers.
@@ -166,19 +447,185 @@ func (s3ObjectStoreGetter) ObjectStore(ctx context.Context,
 	s3Downloader := s3manager.NewDownloaderWithClient(s3Client)
 	s3BatchDeleter := s3manager.NewBatchDeleteWithClient(s3Client)
 	s3Conn := &s3ObjectStore{
-		session:      s3Session,
-		client:       s3Client,
-		uploader:     s3Uploader,
-		downloader:   s3Downloader,
-		batchDeleter: s3BatchDeleter,
-		s3Endpoint:   s3Endpoint,
-		s3Bucket:     s3StoreProfile.S3Bucket,
-		callerTag:    callerTag,
+		session:            s3Session,
+		client:             s3Client,
+		uploader:           s3Uploader,
+		downloader:         s3Downloader,
+		batchDeleter:       s3BatchDeleter,
+		s3Endpoint:         s3Endpoint,
+		s3Bucket:           s3StoreProfile.S3Bucket,
+		keyRoot:            strings.Trim(s3StoreProfile.S3BucketPrefix, "/"),
+		callerTag:          callerTag,
+		sse:                sse,
+		retention:          resolveRetentionConfig(s3StoreProfile),
+		profileFingerprint: fingerprint,
 	}
 
+	sessionCacheMu.Lock()
+	sessionCache[s3ProfileName] = s3Conn
+	sessionCacheMu.Unlock()
+
 	return s3Conn, nil
 }
 
+// profileCacheFingerprint returns an opaque string that changes whenever any
+// setting ObjectStore derives from profile changes, including the
+// resourceVersion of any Secret it references.  ObjectStore compares this
+// against a cached connection's profileFingerprint to decide whether the
+// cache entry is still valid, so that rotating the static-credentials Secret
+// or the CA bundle Secret, or editing the profile itself, takes effect on the
+// next call instead of requiring a process restart.
+func profileCacheFingerprint(ctx context.Context, r client.Reader,
+	profile S3StoreProfile) (string, error) {
+	var s3SecretVersion, caBundleSecretVersion, sseCSecretVersion string
+
+	if profile.S3SecretRef != (corev1.SecretReference{}) {
+		secret := corev1.Secret{}
+		if err := r.Get(ctx,
+			types.NamespacedName{Namespace: profile.S3SecretRef.Namespace, Name: profile.S3SecretRef.Name},
+			&secret); err != nil {
+			return "", fmt.Errorf("failed to get secret %v, %w", profile.S3SecretRef, err)
+		}
+
+		s3SecretVersion = secret.ResourceVersion
+	}
+
+	if profile.CABundleSecretRef != nil {
+		secret := corev1.Secret{}
+		if err := r.Get(ctx,
+			types.NamespacedName{Namespace: profile.CABundleSecretRef.Namespace, Name: profile.CABundleSecretRef.Name},
+			&secret); err != nil {
+			return "", fmt.Errorf("failed to get CA bundle secret %v, %w", *profile.CABundleSecretRef, err)
+		}
+
+		caBundleSecretVersion = secret.ResourceVersion
+	}
+
+	if profile.Encryption.Mode == SSEModeC {
+		secret := corev1.Secret{}
+		ref := profile.Encryption.SSECSecretRef
+		if err := r.Get(ctx, types.NamespacedName{Namespace: ref.Namespace, Name: ref.Name}, &secret); err != nil {
+			return "", fmt.Errorf("failed to get SSE-C key secret %v, %w", ref, err)
+		}
+
+		sseCSecretVersion = secret.ResourceVersion
+	}
+
+	return fmt.Sprintf("%s|%s|%s|%s|%s|%v|%v|%s|%s|%s|%s|%s|%s|%v|%d|%s|%v",
+		profile.S3CompatibleEndpoint, profile.S3Region, profile.S3Bucket,
+		profile.CredentialSource, profile.S3BucketPrefix,
+		profile.ForcePathStyle != nil, aws.BoolValue(profile.ForcePathStyle),
+		s3SecretVersion, caBundleSecretVersion, sseCSecretVersion,
+		profile.Encryption.Mode, profile.Encryption.SSEKMSKeyID, profile.Encryption.SSEKMSEncryptionContext,
+		profile.Insecure, profile.Retention.Days, profile.Retention.Mode, profile.Retention.LegalHold), nil
+}
+
+// warnIfBucketUnencrypted logs a warning if the given bucket does not have
+// any default encryption configuration mandated at the bucket level.  This is
+// advisory only, since profile-connect must still succeed: an empty bucket
+// name (not yet created) is skipped without a lookup or a log entry, and any
+// other lookup error (e.g. a permissions error) is logged and otherwise
+// ignored.
+func warnIfBucketUnencrypted(s3Client *s3.S3, bucket, callerTag string, log logr.Logger) {
+	if bucket == "" {
+		return
+	}
+
+	_, err := s3Client.GetBucketEncryption(&s3.GetBucketEncryptionInput{Bucket: &bucket})
+	if err == nil {
+		return
+	}
+
+	var aerr awserr.Error
+	if errorswrapper.As(err, &aerr) && aerr.Code() == "ServerSideEncryptionConfigurationNotFoundError" {
+		log.Info("bucket does not mandate server-side encryption",
+			"bucket", bucket, "caller", callerTag)
+
+		return
+	}
+
+	log.Info("unable to determine bucket encryption policy",
+		"bucket", bucket, "caller", callerTag, "error", err.Error())
+}
+
+// buildS3HTTPClient constructs the *http.Client used for all requests to the
+// profile's S3 endpoint.  Its tls.Config trusts the system CA pool plus, if
+// CABundleSecretRef is set, the PEM bundle under that Secret's "ca.crt" key;
+// InsecureSkipVerify is set only when the profile explicitly opts into it via
+// Insecure.
+func buildS3HTTPClient(ctx context.Context, r client.Reader,
+	profile S3StoreProfile) (*http.Client, error) {
+	rootCAs, err := x509.SystemCertPool()
+	if err != nil || rootCAs == nil {
+		rootCAs = x509.NewCertPool()
+	}
+
+	if profile.CABundleSecretRef != nil {
+		secret := corev1.Secret{}
+		if err := r.Get(ctx,
+			types.NamespacedName{Namespace: profile.CABundleSecretRef.Namespace, Name: profile.CABundleSecretRef.Name},
+			&secret); err != nil {
+			return nil, fmt.Errorf("failed to get CA bundle secret %v, %w",
+				*profile.CABundleSecretRef, err)
+		}
+
+		caBundle := secret.Data["ca.crt"]
+		if !rootCAs.AppendCertsFromPEM(caBundle) {
+			return nil, fmt.Errorf("failed to parse PEM CA bundle from secret %v",
+				*profile.CABundleSecretRef)
+		}
+	}
+
+	tlsConfig := &tls.Config{
+		RootCAs:            rootCAs,
+		InsecureSkipVerify: profile.Insecure, //nolint:gosec // explicit opt-in via profile.Insecure
+	}
+
+	// Clone http.DefaultTransport rather than starting from a bare
+	// http.Transport{}, so we keep its proxy-from-environment support, dial
+	// and handshake timeouts, and connection-pool tuning; only the TLS config
+	// is ours to override.
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = tlsConfig
+
+	return &http.Client{Transport: transport}, nil
+}
+
+// credentialsForSource builds an *credentials.Credentials for the given
+// non-static credential source, using bootstrapSession to reach STS or EC2
+// instance metadata as needed.  Credentials are refreshed transparently by
+// the returned provider on expiry.
+func credentialsForSource(credSource CredentialSourceType,
+	bootstrapSession *session.Session) (*credentials.Credentials, error) {
+	switch credSource {
+	case CredentialSourceIRSA:
+		roleARN := os.Getenv("AWS_ROLE_ARN")
+		tokenFile := os.Getenv("AWS_WEB_IDENTITY_TOKEN_FILE")
+
+		if roleARN == "" || tokenFile == "" {
+			return nil, fmt.Errorf("AWS_ROLE_ARN and AWS_WEB_IDENTITY_TOKEN_FILE " +
+				"must be set for irsa credential source")
+		}
+
+		return credentials.NewCredentials(stscreds.NewWebIdentityRoleProvider(
+			sts.New(bootstrapSession), roleARN, "ramen", tokenFile)), nil
+	case CredentialSourceEC2Instance:
+		return ec2rolecreds.NewCredentials(bootstrapSession), nil
+	case CredentialSourceChain:
+		return credentials.NewChainCredentials([]credentials.Provider{
+			&credentials.EnvProvider{},
+			&credentials.SharedCredentialsProvider{},
+			stscreds.NewWebIdentityRoleProvider(sts.New(bootstrapSession),
+				os.Getenv("AWS_ROLE_ARN"), "ramen", os.Getenv("AWS_WEB_IDENTITY_TOKEN_FILE")),
+			&ec2rolecreds.EC2RoleProvider{Client: ec2metadata.New(bootstrapSession)},
+		}), nil
+	case CredentialSourceSecret:
+		fallthrough
+	default:
+		return nil, fmt.Errorf("unsupported credential source %q", credSource)
+	}
+}
+
 func GetS3Secret(ctx context.Context, r client.Reader,
 	secretRef corev1.SecretReference) (
 	s3AccessID, s3SecretAccessKey []byte, err error) {
@@ -204,11 +651,63 @@ type s3ObjectStore struct {
 	batchDeleter *s3manager.BatchDelete
 	s3Endpoint   string
 	s3Bucket     string
-	callerTag    string
+	// keyRoot, when non-empty, scopes this store to a prefix of s3Bucket,
+	// allowing multiple Ramen installations to share one bucket.  Set from
+	// S3StoreProfile.S3BucketPrefix, with no leading or trailing slash.
+	keyRoot   string
+	callerTag string
+	sse       sseConfig
+	retention retentionConfig
+	// profileFingerprint is the profileCacheFingerprint computed when this
+	// entry was cached; ObjectStore recomputes it on every call and discards
+	// the cache entry on mismatch, so that rotating a referenced Secret or
+	// editing the profile takes effect without a process restart.
+	profileFingerprint string
+}
+
+// fullKey prepends keyRoot to key, for use in S3 API calls, squashing any
+// consecutive forward slashes in the result to a single slash.
+func (s *s3ObjectStore) fullKey(key string) string {
+	if s.keyRoot == "" {
+		return squashSlashes(key)
+	}
+
+	return squashSlashes(s.keyRoot + "/" + key)
+}
+
+// stripKeyRoot removes a leading keyRoot + "/" from key, undoing fullKey, so
+// that callers of ListKeys/ListVersions see logical, tenant-relative keys
+// regardless of keyRoot.
+func (s *s3ObjectStore) stripKeyRoot(key string) string {
+	if s.keyRoot == "" {
+		return key
+	}
+
+	return strings.TrimPrefix(key, s.keyRoot+"/")
+}
+
+// squashSlashes collapses any run of consecutive forward slashes in s to a
+// single forward slash.
+func squashSlashes(s string) string {
+	out := make([]byte, 0, len(s))
+
+	for i := 0; i < len(s); i++ {
+		if s[i] == '/' && i > 0 && s[i-1] == '/' {
+			continue
+		}
+
+		out = append(out, s[i])
+	}
+
+	return string(out)
 }
 
 // CreateBucket creates the given bucket; does not return an error if the bucket
 // exists already.
+//   - When this store is scoped to a bucket prefix (keyRoot is non-empty), the
+//     bucket is assumed to be shared and pre-existing: CreateBucket performs a
+//     HeadBucket preflight instead, so that tenants without bucket-creation
+//     permissions can still connect.
 func (s *s3ObjectStore) CreateBucket(bucket string) (err error) {
 	if bucket == "" {
 		return fmt.Errorf("empty bucket name for "+
@@ -223,7 +722,25 @@ func (s *s3ObjectStore) CreateBucket(bucket string) (err error) {
 		}
 	}()
 
+	if s.keyRoot != "" {
+		if _, err := s.client.HeadBucket(&s3.HeadBucketInput{Bucket: &bucket}); err != nil {
+			return fmt.Errorf("head bucket preflight failed for %s, %w",
+				bucket, err)
+		}
+
+		return nil
+	}
+
 	cbInput := &s3.CreateBucketInput{Bucket: &bucket}
+
+	// Object Lock can only be turned on for a bucket at creation time; there
+	// is no API to retrofit it afterwards.  ConfigureBucketObjectLock's
+	// PutObjectLockConfiguration call depends on this having been set here
+	// whenever Retention is configured.  This also auto-enables versioning.
+	if s.retention.mode != RetentionModeNone {
+		cbInput.ObjectLockEnabledForBucket = aws.Bool(true)
+	}
+
 	if err = cbInput.Validate(); err != nil {
 		return fmt.Errorf("create bucket input validation failed for %s, err %w",
 			bucket, err)
@@ -279,7 +796,17 @@ func (s *s3ObjectStore) DeleteBucket(bucket string) (
 }
 
 // PurgeBucket empties the content of the given bucket.
-func (s *s3ObjectStore) PurgeBucket(bucket string) (
+//   - When this store is scoped to a bucket prefix (keyRoot is non-empty), only
+//     keys under that prefix are removed and the bucket itself is left intact.
+//   - A delete refused by Object Lock does not abort the purge: it is recorded
+//     and the remaining keys are still attempted.  The bucket is only deleted
+//     if nothing remained locked.
+//   - `force`, when true and this store's Retention mode is GOVERNANCE, passes
+//     BypassGovernanceRetention so locked objects are purged anyway.  The
+//     caller is expected to only ever pass true when explicitly authorized to
+//     do so (e.g. by a RamenConfig flag) — COMPLIANCE-mode objects can never be
+//     bypassed regardless of `force`.
+func (s *s3ObjectStore) PurgeBucket(bucket string, force bool) (
 	err error) {
 	if bucket == "" {
 		return fmt.Errorf("empty bucket name for "+
@@ -305,14 +832,55 @@ func (s *s3ObjectStore) PurgeBucket(bucket string) (
 			s.s3Endpoint, bucket, err)
 	}
 
+	var lockedKeys []string
+
 	for _, key := range keys {
-		err = s.DeleteObjects(key)
-		if err != nil {
-			return fmt.Errorf("failed to delete object %s in bucket %s, %w",
-				key, bucket, err)
+		if err := s.deleteObjects(key, force); err != nil {
+			var locked *ErrObjectLocked
+			if !errorswrapper.As(err, &locked) {
+				return fmt.Errorf("failed to delete object %s in bucket %s, %w",
+					key, bucket, err)
+			}
+
+			lockedKeys = append(lockedKeys, key)
+		}
+	}
+
+	// A versioned bucket retains a version (or a delete marker) for every
+	// key behind deleteObjects above, so those must be purged explicitly or
+	// DeleteBucket below will fail with BucketNotEmpty.
+	versions, err := s.ListVersions("")
+	if err != nil {
+		if !isAwsErrCodeNoSuchBucket(err) {
+			return fmt.Errorf("unable to ListVersions "+
+				"from endpoint %s bucket %s, %w",
+				s.s3Endpoint, bucket, err)
 		}
 	}
 
+	for _, version := range versions {
+		if err := s.deleteObjectVersion(version.Key, version.VersionID, force); err != nil {
+			var locked *ErrObjectLocked
+			if !errorswrapper.As(err, &locked) {
+				return fmt.Errorf("failed to delete version %s of object %s in bucket %s, %w",
+					version.VersionID, version.Key, bucket, err)
+			}
+
+			lockedKeys = append(lockedKeys, version.Key)
+		}
+	}
+
+	if len(lockedKeys) > 0 {
+		return &ErrObjectLocked{Key: strings.Join(lockedKeys, ", "),
+			Err: fmt.Errorf("bucket %s not purged: objects remain under retention", bucket)}
+	}
+
+	// In prefix mode the bucket is shared with other tenants, so purging must
+	// only remove this tenant's keys, never the bucket itself.
+	if s.keyRoot != "" {
+		return nil
+	}
+
 	err = s.DeleteBucket(bucket)
 	if err != nil {
 		return fmt.Errorf("failed to delete bucket %s, %w",
@@ -345,17 +913,18 @@ func (s *s3ObjectStore) UploadTypedObject(keyPrefix, keySuffix string,
 }
 
 // UploadObject uploads the given object to the bucket with the given key.
-// - OK to call UploadObject() concurrently from multiple goroutines safely.
-// - Upload may fail due to many reasons: RequestError (connection error),
-//   NoSuchBucket, NoSuchKey, InvalidParameter (e.g., empty key), etc.
-// - Multiple consecutive forward slashes in the key are sqaushed to
-//   a single forward slash, for each such occurrence
-// - Any formatting changes to this method should also be reflected in the
-//   DownloadObject() method
+//   - OK to call UploadObject() concurrently from multiple goroutines safely.
+//   - Upload may fail due to many reasons: RequestError (connection error),
+//     NoSuchBucket, NoSuchKey, InvalidParameter (e.g., empty key), etc.
+//   - Multiple consecutive forward slashes in the key are sqaushed to
+//     a single forward slash, for each such occurrence
+//   - Any formatting changes to this method should also be reflected in the
+//     DownloadObject() method
 func (s *s3ObjectStore) UploadObject(key string,
 	uploadContent interface{}) error {
 	encodedUploadContent := &bytes.Buffer{}
 	bucket := s.s3Bucket
+	fullKey := s.fullKey(key)
 
 	gzWriter := gzip.NewWriter(encodedUploadContent)
 	if err := json.NewEncoder(gzWriter).Encode(uploadContent); err != nil {
@@ -368,11 +937,15 @@ func (s *s3ObjectStore) UploadObject(key string,
 			bucket, key, err)
 	}
 
-	if _, err := s.uploader.Upload(&s3manager.UploadInput{
+	uploadInput := &s3manager.UploadInput{
 		Bucket: &bucket,
-		Key:    &key,
+		Key:    &fullKey,
 		Body:   encodedUploadContent,
-	}); err != nil {
+	}
+	s.applySSEUpload(uploadInput)
+	s.applyObjectLockUpload(uploadInput)
+
+	if _, err := s.uploader.Upload(uploadInput); err != nil {
 		return fmt.Errorf("failed to upload data of %s:%s, %w",
 			bucket, key, err)
 	}
@@ -380,6 +953,61 @@ func (s *s3ObjectStore) UploadObject(key string,
 	return nil
 }
 
+// applyObjectLockUpload sets the Object Lock fields of uploadInput to match
+// s.retention.  A no-op if Retention is unconfigured.
+func (s *s3ObjectStore) applyObjectLockUpload(uploadInput *s3manager.UploadInput) {
+	if s.retention.mode == RetentionModeNone {
+		return
+	}
+
+	uploadInput.ObjectLockMode = aws.String(string(s.retention.mode))
+
+	if s.retention.days > 0 {
+		retainUntil := time.Now().AddDate(0, 0, int(s.retention.days))
+		uploadInput.ObjectLockRetainUntilDate = &retainUntil
+	}
+
+	if s.retention.legalHold {
+		uploadInput.ObjectLockLegalHoldStatus = aws.String(s3.ObjectLockLegalHoldStatusOn)
+	}
+}
+
+// applySSEUpload sets the server-side-encryption fields of uploadInput to
+// match s.sse.  Any formatting changes here should also be reflected in
+// applySSEDownload().
+func (s *s3ObjectStore) applySSEUpload(uploadInput *s3manager.UploadInput) {
+	switch s.sse.mode {
+	case SSEModeS3:
+		uploadInput.ServerSideEncryption = aws.String(s3.ServerSideEncryptionAes256)
+	case SSEModeKMS:
+		uploadInput.ServerSideEncryption = aws.String(s3.ServerSideEncryptionAwsKms)
+		uploadInput.SSEKMSKeyId = aws.String(s.sse.kmsKeyID)
+
+		if s.sse.kmsContext != "" {
+			uploadInput.SSEKMSEncryptionContext = aws.String(
+				base64.StdEncoding.EncodeToString([]byte(s.sse.kmsContext)))
+		}
+	case SSEModeC:
+		uploadInput.SSECustomerAlgorithm = aws.String(s3.ServerSideEncryptionAes256)
+		uploadInput.SSECustomerKey = aws.String(string(s.sse.customerKey))
+		uploadInput.SSECustomerKeyMD5 = aws.String(s.sse.customerKeyMD5)
+	case SSEModeNone:
+	}
+}
+
+// applySSEDownload sets the customer-supplied-key fields of getObjectInput
+// needed to download an SSE-C encrypted object.  SSE-S3 and SSE-KMS objects
+// require no special handling on download.
+func (s *s3ObjectStore) applySSEDownload(getObjectInput *s3.GetObjectInput) {
+	if s.sse.mode != SSEModeC {
+		return
+	}
+
+	getObjectInput.SSECustomerAlgorithm = aws.String(s3.ServerSideEncryptionAes256)
+	getObjectInput.SSECustomerKey = aws.String(string(s.sse.customerKey))
+	getObjectInput.SSECustomerKeyMD5 = aws.String(s.sse.customerKeyMD5)
+}
+
 // VerifyPVUpload verifies that the PV in the input matches the PV object
 // with the given keySuffix in the bucket.
 func (s *s3ObjectStore) VerifyPVUpload(pvKeyPrefix, pvKeySuffix string,
@@ -406,14 +1034,16 @@ func (s *s3ObjectStore) VerifyPVUpload(pvKeyPrefix, pvKeySuffix string,
 }
 
 // DownloadPVs downloads all PVs in the bucket.
-// - Downloads PVs with the given key prefix.
-// - If bucket doesn't exists, will return ErrCodeNoSuchBucket "NoSuchBucket"
-func (s *s3ObjectStore) DownloadPVs(pvKeyPrefix string) (
+//   - Downloads PVs with the given key prefix.
+//   - If bucket doesn't exists, will return ErrCodeNoSuchBucket "NoSuchBucket"
+//   - If `at` is given, downloads, for each key, the newest version as of that
+//     time instead of the current version; at most one `at` may be given.
+func (s *s3ObjectStore) DownloadPVs(pvKeyPrefix string, at ...time.Time) (
 	pvList []corev1.PersistentVolume, err error) {
 	objectType := reflect.TypeOf(corev1.PersistentVolume{})
 	bucket := s.s3Bucket
 
-	result, err := s.DownloadTypedObjects(pvKeyPrefix, objectType)
+	result, err := s.DownloadTypedObjects(pvKeyPrefix, objectType, at...)
 	if err != nil {
 		return nil, fmt.Errorf("unable to download: %s, %w", bucket, err)
 	}
@@ -428,14 +1058,20 @@ func (s *s3ObjectStore) DownloadPVs(pvKeyPrefix string) (
 
 // DownloadTypedObjects downloads all objects of the given objectType that have
 // the given key prefix followed by the given object's objectType keyInfix.
-// - Example key prefix:  namespace/vrgName/
-//   Example key infix:  v1.PersistentVolumeClaim/
-//   Example new key prefix: namespace/vrgName/v1.PersistentVolumeClaim/
-// - Objects being downloaded should meet the decoding expectations of
-//   the DownloadObject() method.
-// - Returns a []objectType
+//   - Example key prefix:  namespace/vrgName/
+//     Example key infix:  v1.PersistentVolumeClaim/
+//     Example new key prefix: namespace/vrgName/v1.PersistentVolumeClaim/
+//   - Objects being downloaded should meet the decoding expectations of
+//     the DownloadObject() method.
+//   - If `at` is given, downloads, for each key, the newest version as of that
+//     time instead of the current version; at most one `at` may be given.
+//   - Returns a []objectType
 func (s *s3ObjectStore) DownloadTypedObjects(keyPrefix string,
-	objectType reflect.Type) (interface{}, error) {
+	objectType reflect.Type, at ...time.Time) (interface{}, error) {
+	if len(at) > 1 {
+		return nil, fmt.Errorf("DownloadTypedObjects accepts at most one `at` time, got %d", len(at))
+	}
+
 	keyInfix := objectType.String() + "/"
 	newKeyPrefix := keyPrefix + keyInfix
 	bucket := s.s3Bucket
@@ -452,7 +1088,14 @@ func (s *s3ObjectStore) DownloadTypedObjects(keyPrefix string,
 
 	for i := range keys {
 		objectReceiver := objects.Index(i).Addr().Interface()
-		if err := s.DownloadObject(keys[i], objectReceiver); err != nil {
+
+		if len(at) == 0 {
+			err = s.DownloadObject(keys[i], objectReceiver)
+		} else {
+			err = s.downloadObjectAt(keys[i], at[0], objectReceiver)
+		}
+
+		if err != nil {
 			return nil, fmt.Errorf("unable to DownloadObject from "+
 				"endpoint %s bucket %s key %s, %w",
 				s.s3Endpoint, bucket, keys[i], err)
@@ -463,6 +1106,53 @@ func (s *s3ObjectStore) DownloadTypedObjects(keyPrefix string,
 	return objects.Interface(), nil
 }
 
+// downloadObjectAt downloads the newest version of key whose LastModified is
+// at or before `at`.  When the bucket has no versioning enabled, ListVersions
+// returns only the current version, which is used if it was last modified at
+// or before `at`; otherwise no version qualifies and this returns an error.
+// If the newest qualifying version is a delete marker, key did not exist at
+// `at`, and this returns an error rather than attempting to download it.
+func (s *s3ObjectStore) downloadObjectAt(key string, at time.Time,
+	downloadContent interface{}) error {
+	versions, err := s.ListVersions(key)
+	if err != nil {
+		return fmt.Errorf("unable to ListVersions of %s, %w", key, err)
+	}
+
+	best := pickVersionAt(versions, key, at)
+	if best == nil {
+		return fmt.Errorf("no version of %s found at or before %s", key, at)
+	}
+
+	if best.IsDeleteMarker {
+		return fmt.Errorf("%s was deleted as of %s", key, at)
+	}
+
+	return s.DownloadObjectVersion(key, best.VersionID, downloadContent)
+}
+
+// pickVersionAt returns the newest of versions, restricted to key, whose
+// LastModified is at or before `at`, or nil if none qualifies.  The result
+// may be a delete marker, which callers must check for and treat as "key
+// absent at that time" rather than passing it to DownloadObjectVersion,
+// since S3 rejects attempts to download a delete marker.
+func pickVersionAt(versions []ObjectVersion, key string, at time.Time) *ObjectVersion {
+	var best *ObjectVersion
+
+	for i := range versions {
+		v := versions[i]
+		if v.Key != key || v.LastModified.After(at) {
+			continue
+		}
+
+		if best == nil || v.LastModified.After(best.LastModified) {
+			best = &versions[i]
+		}
+	}
+
+	return best
+}
+
 // ListKeys lists the keys (of objects) with the given keyPrefix in the bucket.
 // - If bucket doesn't exists, will return ErrCodeNoSuchBucket "NoSuchBucket"
 // - Refer to aws documentation of s3.ListObjectsV2Input for more list options
@@ -471,11 +1161,12 @@ func (s *s3ObjectStore) ListKeys(keyPrefix string) (
 	var nextContinuationToken *string
 
 	bucket := s.s3Bucket
+	fullPrefix := s.fullKey(keyPrefix)
 
 	for gotAllObjects := false; !gotAllObjects; {
 		result, err := s.client.ListObjectsV2(&s3.ListObjectsV2Input{
 			Bucket:            &bucket,
-			Prefix:            &keyPrefix,
+			Prefix:            &fullPrefix,
 			ContinuationToken: nextContinuationToken,
 		})
 		if err != nil {
@@ -485,7 +1176,7 @@ func (s *s3ObjectStore) ListKeys(keyPrefix string) (
 		}
 
 		for _, entry := range result.Contents {
-			keys = append(keys, *entry.Key)
+			keys = append(keys, s.stripKeyRoot(*entry.Key))
 		}
 
 		if *result.IsTruncated {
@@ -498,29 +1189,200 @@ func (s *s3ObjectStore) ListKeys(keyPrefix string) (
 	return
 }
 
+// EnableBucketVersioning turns on S3 object versioning for the bucket.  Once
+// enabled, versioning cannot be disabled, only suspended; Ramen never
+// suspends it.
+func (s *s3ObjectStore) EnableBucketVersioning(bucket string) error {
+	if bucket == "" {
+		return fmt.Errorf("empty bucket name for "+
+			"endpoint %s caller %s", s.s3Endpoint, s.callerTag)
+	}
+
+	_, err := s.client.PutBucketVersioning(&s3.PutBucketVersioningInput{
+		Bucket: &bucket,
+		VersioningConfiguration: &s3.VersioningConfiguration{
+			Status: aws.String(s3.BucketVersioningStatusEnabled),
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to enable versioning on bucket %s, %w",
+			bucket, err)
+	}
+
+	return nil
+}
+
+// ConfigureBucketObjectLock turns on the bucket's default Object Lock
+// retention per s.retention.  A no-op if Retention is unconfigured.
+func (s *s3ObjectStore) ConfigureBucketObjectLock(bucket string) error {
+	if bucket == "" {
+		return fmt.Errorf("empty bucket name for "+
+			"endpoint %s caller %s", s.s3Endpoint, s.callerTag)
+	}
+
+	if s.retention.mode == RetentionModeNone {
+		return nil
+	}
+
+	defaultRetention := &s3.DefaultRetention{
+		Mode: aws.String(string(s.retention.mode)),
+	}
+	if s.retention.days > 0 {
+		defaultRetention.Days = aws.Int64(s.retention.days)
+	}
+
+	_, err := s.client.PutObjectLockConfiguration(&s3.PutObjectLockConfigurationInput{
+		Bucket: &bucket,
+		ObjectLockConfiguration: &s3.ObjectLockConfiguration{
+			ObjectLockEnabled: aws.String(s3.ObjectLockEnabledEnabled),
+			Rule: &s3.ObjectLockRule{
+				DefaultRetention: defaultRetention,
+			},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to configure object lock on bucket %s, %w",
+			bucket, err)
+	}
+
+	return nil
+}
+
+// ListVersions lists every version (including delete markers) of every key
+// with the given keyPrefix in the bucket, across all pages.
+func (s *s3ObjectStore) ListVersions(keyPrefix string) (
+	versions []ObjectVersion, err error) {
+	var keyMarker, versionIDMarker *string
+
+	bucket := s.s3Bucket
+	fullPrefix := s.fullKey(keyPrefix)
+
+	for gotAllVersions := false; !gotAllVersions; {
+		result, err := s.client.ListObjectVersions(&s3.ListObjectVersionsInput{
+			Bucket:          &bucket,
+			Prefix:          &fullPrefix,
+			KeyMarker:       keyMarker,
+			VersionIdMarker: versionIDMarker,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list object versions in bucket %s:%s, %w",
+				bucket, keyPrefix, err)
+		}
+
+		for _, v := range result.Versions {
+			versions = append(versions, ObjectVersion{
+				Key:          s.stripKeyRoot(aws.StringValue(v.Key)),
+				VersionID:    aws.StringValue(v.VersionId),
+				LastModified: aws.TimeValue(v.LastModified),
+				IsLatest:     aws.BoolValue(v.IsLatest),
+			})
+		}
+
+		for _, m := range result.DeleteMarkers {
+			versions = append(versions, ObjectVersion{
+				Key:            s.stripKeyRoot(aws.StringValue(m.Key)),
+				VersionID:      aws.StringValue(m.VersionId),
+				LastModified:   aws.TimeValue(m.LastModified),
+				IsLatest:       aws.BoolValue(m.IsLatest),
+				IsDeleteMarker: true,
+			})
+		}
+
+		if aws.BoolValue(result.IsTruncated) {
+			keyMarker = result.NextKeyMarker
+			versionIDMarker = result.NextVersionIdMarker
+		} else {
+			gotAllVersions = true
+		}
+	}
+
+	return
+}
+
+// DeleteObjectVersion deletes the given version of key from the bucket.
+func (s *s3ObjectStore) DeleteObjectVersion(key, versionID string) error {
+	return s.deleteObjectVersion(key, versionID, false)
+}
+
+// deleteObjectVersion is the shared implementation backing
+// DeleteObjectVersion and PurgeBucket's force-purge path.
+func (s *s3ObjectStore) deleteObjectVersion(key, versionID string, bypassGovernance bool) error {
+	bucket := s.s3Bucket
+	fullKey := s.fullKey(key)
+	bypass := bypassGovernance && s.retention.mode == RetentionModeGovernance
+
+	_, err := s.client.DeleteObject(&s3.DeleteObjectInput{
+		Bucket:                    &bucket,
+		Key:                       &fullKey,
+		VersionId:                 &versionID,
+		BypassGovernanceRetention: aws.Bool(bypass),
+	})
+	if err != nil {
+		if isObjectLockedErr(err) {
+			return &ErrObjectLocked{Key: key, Err: err}
+		}
+
+		return fmt.Errorf("failed to delete version %s of object %s:%s, %w",
+			versionID, bucket, key, err)
+	}
+
+	return nil
+}
+
 // DownloadObject downloads an object from the bucket with the given key,
 // unzips, decodes the json blob and stores the downloaded object in the
 // downloadContent parameter.  The caller is expected to use the correct type of
 // downloadContent parameter.
-// - OK to call DownloadObject() concurrently from multiple goroutines safely.
-// - Assumes that the object in S3 store are json blobs that have been then
-//   gzipped and hence, will unzip & decode the json blobs before returning it.
-// - Only those type field name in the downloaded json blob that are also
-//   present in the downloadContent type will be filled; other fields will be
-//   dropped without returning any error.  More info at documentation of
-//   json.Unmarshall().
-// - Download may fail due to many reasons: RequestError (connection error),
-//   NoSuchBucket, NoSuchKey, invalid gzip header, json unmarshall error,
-//   InvalidParameter (e.g., empty key), etc.
+//   - OK to call DownloadObject() concurrently from multiple goroutines safely.
+//   - Assumes that the object in S3 store are json blobs that have been then
+//     gzipped and hence, will unzip & decode the json blobs before returning it.
+//   - Only those type field name in the downloaded json blob that are also
+//     present in the downloadContent type will be filled; other fields will be
+//     dropped without returning any error.  More info at documentation of
+//     json.Unmarshall().
+//   - Download may fail due to many reasons: RequestError (connection error),
+//     NoSuchBucket, NoSuchKey, invalid gzip header, json unmarshall error,
+//     InvalidParameter (e.g., empty key), etc.
 func (s *s3ObjectStore) DownloadObject(key string,
 	downloadContent interface{}) error {
 	bucket := s.s3Bucket
-	writerAt := &aws.WriteAtBuffer{}
+	fullKey := s.fullKey(key)
 
-	if _, err := s.downloader.Download(writerAt, &s3.GetObjectInput{
+	getObjectInput := &s3.GetObjectInput{
 		Bucket: &bucket,
-		Key:    &key,
-	}); err != nil {
+		Key:    &fullKey,
+	}
+	s.applySSEDownload(getObjectInput)
+
+	return s.download(getObjectInput, downloadContent)
+}
+
+// DownloadObjectVersion downloads the given version of key, unzips, decodes
+// the json blob and stores the downloaded object in downloadContent, as
+// DownloadObject does for the current version.
+func (s *s3ObjectStore) DownloadObjectVersion(key, versionID string,
+	downloadContent interface{}) error {
+	bucket := s.s3Bucket
+	fullKey := s.fullKey(key)
+
+	getObjectInput := &s3.GetObjectInput{
+		Bucket:    &bucket,
+		Key:       &fullKey,
+		VersionId: &versionID,
+	}
+	s.applySSEDownload(getObjectInput)
+
+	return s.download(getObjectInput, downloadContent)
+}
+
+// download is the shared implementation backing DownloadObject and
+// DownloadObjectVersion.
+func (s *s3ObjectStore) download(getObjectInput *s3.GetObjectInput,
+	downloadContent interface{}) error {
+	bucket, key := s.s3Bucket, aws.StringValue(getObjectInput.Key)
+	writerAt := &aws.WriteAtBuffer{}
+
+	if _, err := s.downloader.Download(writerAt, getObjectInput); err != nil {
 		return fmt.Errorf("failed to download data of %s:%s, %w",
 			bucket, key, err)
 	}
@@ -547,7 +1409,16 @@ func (s *s3ObjectStore) DownloadObject(key string,
 // DeleteObjects() deletes from the bucket any objects that have the given
 // the keyPrefix.  If the bucket doesn't exists, will return
 // ErrCodeNoSuchBucket "NoSuchBucket".
-func (s *s3ObjectStore) DeleteObjects(keyPrefix string) (
+func (s *s3ObjectStore) DeleteObjects(keyPrefix string) error {
+	return s.deleteObjects(keyPrefix, false)
+}
+
+// deleteObjects is the shared implementation backing DeleteObjects and
+// PurgeBucket's force-purge path.  When bypassGovernance is true and this
+// store's Retention mode is GOVERNANCE, locked objects are deleted anyway;
+// COMPLIANCE-mode objects can never be bypassed.  A delete refused due to
+// Object Lock is reported as *ErrObjectLocked rather than bailing the batch.
+func (s *s3ObjectStore) deleteObjects(keyPrefix string, bypassGovernance bool) (
 	err error) {
 	bucket := s.s3Bucket
 
@@ -558,14 +1429,17 @@ func (s *s3ObjectStore) DeleteObjects(keyPrefix string) (
 			s.s3Endpoint, bucket, keyPrefix, err)
 	}
 
+	bypass := bypassGovernance && s.retention.mode == RetentionModeGovernance
+
 	numObjects := len(keys)
 	delObjects := make([]s3manager.BatchDeleteObject, numObjects)
 
 	for i, key := range keys {
 		delObjects[i] = s3manager.BatchDeleteObject{
 			Object: &s3.DeleteObjectInput{
-				Key:    aws.String(key),
-				Bucket: aws.String(bucket),
+				Key:                       aws.String(s.fullKey(key)),
+				Bucket:                    aws.String(bucket),
+				BypassGovernanceRetention: aws.Bool(bypass),
 			},
 		}
 	}
@@ -573,6 +1447,10 @@ func (s *s3ObjectStore) DeleteObjects(keyPrefix string) (
 	if err = s.batchDeleter.Delete(aws.BackgroundContext(), &s3manager.DeleteObjectsIterator{
 		Objects: delObjects,
 	}); err != nil {
+		if isObjectLockedErr(err) {
+			return &ErrObjectLocked{Key: keyPrefix, Err: err}
+		}
+
 		return fmt.Errorf("unable to DeleteObjects "+
 			"from endpoint %s bucket %s keyPrefix %s, %w",
 			s.s3Endpoint, bucket, keyPrefix, err)
@@ -0,0 +1,295 @@
+/*
+Copyright 2021 The RamenDR authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+	http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3manager"
+)
+
+func TestApplySSEUploadAndDownload(t *testing.T) {
+	customerKey := make([]byte, 32)
+	for i := range customerKey {
+		customerKey[i] = byte(i)
+	}
+
+	tests := []struct {
+		name string
+		sse  sseConfig
+	}{
+		{name: "none", sse: sseConfig{mode: SSEModeNone}},
+		{name: "sseS3", sse: sseConfig{mode: SSEModeS3}},
+		{name: "sseKMS", sse: sseConfig{mode: SSEModeKMS, kmsKeyID: "key-id", kmsContext: `{"a":"b"}`}},
+		{
+			name: "sseC",
+			sse: sseConfig{
+				mode:           SSEModeC,
+				customerKey:    customerKey,
+				customerKeyMD5: "deadbeef",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &s3ObjectStore{sse: tt.sse}
+
+			uploadInput := &s3manager.UploadInput{}
+			s.applySSEUpload(uploadInput)
+
+			getObjectInput := &s3.GetObjectInput{}
+			s.applySSEDownload(getObjectInput)
+
+			switch tt.sse.mode {
+			case SSEModeNone:
+				if uploadInput.ServerSideEncryption != nil {
+					t.Errorf("expected no ServerSideEncryption, got %v", *uploadInput.ServerSideEncryption)
+				}
+
+				if getObjectInput.SSECustomerKey != nil {
+					t.Errorf("expected no SSECustomerKey on download, got %v", *getObjectInput.SSECustomerKey)
+				}
+			case SSEModeS3:
+				if aws.StringValue(uploadInput.ServerSideEncryption) != s3.ServerSideEncryptionAes256 {
+					t.Errorf("expected AES256 ServerSideEncryption, got %v", uploadInput.ServerSideEncryption)
+				}
+			case SSEModeKMS:
+				if aws.StringValue(uploadInput.ServerSideEncryption) != s3.ServerSideEncryptionAwsKms {
+					t.Errorf("expected aws:kms ServerSideEncryption, got %v", uploadInput.ServerSideEncryption)
+				}
+
+				if aws.StringValue(uploadInput.SSEKMSKeyId) != tt.sse.kmsKeyID {
+					t.Errorf("expected SSEKMSKeyId %s, got %v", tt.sse.kmsKeyID, uploadInput.SSEKMSKeyId)
+				}
+
+				if uploadInput.SSEKMSEncryptionContext == nil {
+					t.Errorf("expected SSEKMSEncryptionContext to be set")
+				}
+			case SSEModeC:
+				if aws.StringValue(uploadInput.SSECustomerKey) != string(tt.sse.customerKey) {
+					t.Errorf("upload customer key mismatch")
+				}
+
+				if aws.StringValue(uploadInput.SSECustomerKeyMD5) != tt.sse.customerKeyMD5 {
+					t.Errorf("upload customer key MD5 mismatch")
+				}
+				// DownloadObject must present the same customer key/MD5 as
+				// UploadObject did, or S3 rejects the GetObject call for an
+				// SSE-C object with a key mismatch.
+				if aws.StringValue(getObjectInput.SSECustomerKey) != aws.StringValue(uploadInput.SSECustomerKey) {
+					t.Errorf("download customer key does not match upload customer key")
+				}
+
+				if aws.StringValue(getObjectInput.SSECustomerKeyMD5) != aws.StringValue(uploadInput.SSECustomerKeyMD5) {
+					t.Errorf("download customer key MD5 does not match upload customer key MD5")
+				}
+			}
+		})
+	}
+}
+
+func TestPickVersionAt(t *testing.T) {
+	t0 := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	versions := []ObjectVersion{
+		{Key: "pv1", VersionID: "v1", LastModified: t0},
+		{Key: "pv1", VersionID: "v2", LastModified: t0.Add(time.Hour)},
+		{Key: "pv1", VersionID: "v3", LastModified: t0.Add(2 * time.Hour), IsLatest: true},
+		{Key: "pv2", VersionID: "other-key", LastModified: t0.Add(2 * time.Hour)},
+	}
+
+	tests := []struct {
+		name      string
+		at        time.Time
+		wantVerID string
+	}{
+		{name: "before any version", at: t0.Add(-time.Minute), wantVerID: ""},
+		{name: "exact match on earliest", at: t0, wantVerID: "v1"},
+		{name: "between versions picks older", at: t0.Add(90 * time.Minute), wantVerID: "v2"},
+		{name: "at or after latest picks latest", at: t0.Add(3 * time.Hour), wantVerID: "v3"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := pickVersionAt(versions, "pv1", tt.at)
+
+			if tt.wantVerID == "" {
+				if got != nil {
+					t.Errorf("expected no version, got %v", got.VersionID)
+				}
+
+				return
+			}
+
+			if got == nil {
+				t.Fatalf("expected version %s, got none", tt.wantVerID)
+			}
+
+			if got.VersionID != tt.wantVerID {
+				t.Errorf("expected version %s, got %s", tt.wantVerID, got.VersionID)
+			}
+		})
+	}
+}
+
+func TestPickVersionAtDeleteMarker(t *testing.T) {
+	t0 := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	versions := []ObjectVersion{
+		{Key: "pv1", VersionID: "v1", LastModified: t0},
+		{Key: "pv1", VersionID: "del1", LastModified: t0.Add(time.Hour), IsDeleteMarker: true, IsLatest: true},
+	}
+
+	// Querying after the delete marker must surface it, not the older real
+	// version, so callers can recognize the key was deleted as of `at`.
+	got := pickVersionAt(versions, "pv1", t0.Add(2*time.Hour))
+	if got == nil {
+		t.Fatalf("expected the delete marker, got none")
+	}
+
+	if !got.IsDeleteMarker {
+		t.Errorf("expected IsDeleteMarker version %s, got real version %s", "del1", got.VersionID)
+	}
+
+	// Querying before the delete marker must still surface the real version.
+	got = pickVersionAt(versions, "pv1", t0)
+	if got == nil || got.IsDeleteMarker {
+		t.Errorf("expected real version v1, got %v", got)
+	}
+}
+
+func TestFullKeyAndStripKeyRoot(t *testing.T) {
+	tests := []struct {
+		name         string
+		keyRoot      string
+		key          string
+		wantFull     string
+		wantStripped string
+	}{
+		{
+			name: "no prefix", keyRoot: "", key: "pv1/manifest.yaml",
+			wantFull: "pv1/manifest.yaml", wantStripped: "pv1/manifest.yaml",
+		},
+		{
+			name: "with prefix", keyRoot: "tenant-a", key: "pv1/manifest.yaml",
+			wantFull: "tenant-a/pv1/manifest.yaml", wantStripped: "pv1/manifest.yaml",
+		},
+		{
+			name: "prefix squashes leading slash", keyRoot: "tenant-a", key: "/pv1",
+			wantFull: "tenant-a/pv1", wantStripped: "pv1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &s3ObjectStore{keyRoot: tt.keyRoot}
+
+			full := s.fullKey(tt.key)
+			if full != tt.wantFull {
+				t.Errorf("fullKey(%q) = %q, want %q", tt.key, full, tt.wantFull)
+			}
+
+			if stripped := s.stripKeyRoot(full); stripped != tt.wantStripped {
+				t.Errorf("stripKeyRoot(%q) = %q, want %q", full, stripped, tt.wantStripped)
+			}
+		})
+	}
+}
+
+func newTestBootstrapSession(t *testing.T) *session.Session {
+	t.Helper()
+
+	// The bootstrap session must never carry the S3-compatible Endpoint, or
+	// sts.New/ec2metadata.New derived from it would be pointed at the S3
+	// endpoint instead of the real STS/instance-metadata hosts.
+	bootstrapSession, err := session.NewSession(&aws.Config{Region: aws.String("us-east-1")})
+	if err != nil {
+		t.Fatalf("failed to create bootstrap session: %v", err)
+	}
+
+	if aws.StringValue(bootstrapSession.Config.Endpoint) != "" {
+		t.Fatalf("bootstrap session unexpectedly has Endpoint %q set",
+			aws.StringValue(bootstrapSession.Config.Endpoint))
+	}
+
+	return bootstrapSession
+}
+
+func TestCredentialsForSourceIRSA(t *testing.T) {
+	bootstrapSession := newTestBootstrapSession(t)
+
+	t.Run("missing env vars", func(t *testing.T) {
+		t.Setenv("AWS_ROLE_ARN", "")
+		t.Setenv("AWS_WEB_IDENTITY_TOKEN_FILE", "")
+
+		if _, err := credentialsForSource(CredentialSourceIRSA, bootstrapSession); err == nil {
+			t.Errorf("expected an error when AWS_ROLE_ARN/AWS_WEB_IDENTITY_TOKEN_FILE are unset")
+		}
+	})
+
+	t.Run("env vars set", func(t *testing.T) {
+		t.Setenv("AWS_ROLE_ARN", "arn:aws:iam::123456789012:role/ramen")
+		t.Setenv("AWS_WEB_IDENTITY_TOKEN_FILE", "/var/run/secrets/token")
+
+		creds, err := credentialsForSource(CredentialSourceIRSA, bootstrapSession)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if creds == nil {
+			t.Errorf("expected non-nil credentials")
+		}
+	})
+}
+
+func TestCredentialsForSourceEC2Instance(t *testing.T) {
+	bootstrapSession := newTestBootstrapSession(t)
+
+	creds, err := credentialsForSource(CredentialSourceEC2Instance, bootstrapSession)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if creds == nil {
+		t.Errorf("expected non-nil credentials")
+	}
+}
+
+func TestCredentialsForSourceChain(t *testing.T) {
+	bootstrapSession := newTestBootstrapSession(t)
+
+	creds, err := credentialsForSource(CredentialSourceChain, bootstrapSession)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if creds == nil {
+		t.Errorf("expected non-nil credentials")
+	}
+}
+
+func TestCredentialsForSourceUnsupported(t *testing.T) {
+	bootstrapSession := newTestBootstrapSession(t)
+
+	if _, err := credentialsForSource(CredentialSourceSecret, bootstrapSession); err == nil {
+		t.Errorf("expected an error for credential source %q, which requires a Secret", CredentialSourceSecret)
+	}
+
+	if _, err := credentialsForSource(CredentialSourceType("bogus"), bootstrapSession); err == nil {
+		t.Errorf("expected an error for an unsupported credential source")
+	}
+}